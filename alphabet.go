@@ -0,0 +1,109 @@
+package spg
+
+import "sort"
+
+// Alphabet is a registrable named character set that CharRecipe can draw
+// from. The built-in classes (Uppers, Lowers, ...) are registered with
+// their historical CTFlag so existing Allow/Require/Exclude bitmasks keep
+// working unchanged; alphabets with no corresponding CTFlag (Base58, Hex,
+// ...) are selected purely by name via AllowNamed/ExcludeNamed.
+type Alphabet struct {
+	Name  string
+	Runes []rune
+	Flag  CTFlag // 0 if this alphabet has no legacy CTFlag
+}
+
+// alphabetRegistry holds every Alphabet known to the package, keyed by
+// Name, and is populated by RegisterAlphabet.
+var alphabetRegistry = map[string]Alphabet{}
+
+// RegisterAlphabet adds a to the registry, or replaces the alphabet
+// previously registered under the same Name.
+func RegisterAlphabet(a Alphabet) {
+	alphabetRegistry[a.Name] = a
+}
+
+func init() {
+	RegisterAlphabet(Alphabet{Name: "Uppers", Runes: []rune(CTUpper), Flag: Uppers})
+	RegisterAlphabet(Alphabet{Name: "Lowers", Runes: []rune(CTLower), Flag: Lowers})
+	RegisterAlphabet(Alphabet{Name: "Digits", Runes: []rune(CTDigits), Flag: Digits})
+	RegisterAlphabet(Alphabet{Name: "Symbols", Runes: []rune(CTSymbols), Flag: Symbols})
+	RegisterAlphabet(Alphabet{Name: "Ambiguous", Runes: []rune(CTAmbiguous), Flag: Ambiguous})
+	RegisterAlphabet(Alphabet{Name: "WhiteSpace", Runes: []rune(CTWhiteSpace), Flag: WhiteSpace})
+
+	// API-key-shaped alphabets, selected by name rather than CTFlag since
+	// they don't correspond to a password character class.
+	RegisterAlphabet(Alphabet{Name: "Base32", Runes: []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ234567")})
+	RegisterAlphabet(Alphabet{Name: "CrockfordBase32", Runes: []rune("0123456789ABCDEFGHJKMNPQRSTVWXYZ")})
+	RegisterAlphabet(Alphabet{Name: "Base58", Runes: []rune("123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz")})
+	RegisterAlphabet(Alphabet{Name: "Base62", Runes: []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789")})
+	RegisterAlphabet(Alphabet{Name: "Base64URL", Runes: []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_")})
+	RegisterAlphabet(Alphabet{Name: "Hex", Runes: []rune("0123456789abcdef")})
+}
+
+// alphabetNames returns the registry's keys in sorted order, so that
+// buildCharacterList and classAlphabet iterate it deterministically.
+func alphabetNames() []string {
+	names := make([]string, 0, len(alphabetRegistry))
+	for name := range alphabetRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AllowNamed adds the given registered alphabet names to the set this
+// recipe draws from, in addition to whatever Allow/Require already select.
+func (r *CharRecipe) AllowNamed(names []string) {
+	r.AllowNames = append(r.AllowNames, names...)
+}
+
+// ExcludeNamed removes the given registered alphabets' characters from the
+// generated alphabet, in addition to whatever Exclude already removes.
+func (r *CharRecipe) ExcludeNamed(names []string) {
+	r.ExcludeNames = append(r.ExcludeNames, names...)
+}
+
+// nameIn reports whether name appears in names.
+func nameIn(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// dedupeRunes returns ab with duplicate runes removed, preserving the
+// order of first occurrence. This matters once alphabets can overlap (for
+// example Allow: Digits combined with AllowNamed("Base62")): without it, a
+// rune present in two selected alphabets would be drawn disproportionately
+// often and Entropy would overstate the effective alphabet size.
+func dedupeRunes(ab []rune) []rune {
+	seen := make(map[rune]bool, len(ab))
+	out := make([]rune, 0, len(ab))
+	for _, c := range ab {
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		out = append(out, c)
+	}
+	return out
+}
+
+// subtractRunes returns the runes in ab that do not appear in exclude.
+func subtractRunes(ab, exclude []rune) []rune {
+	excluded := make(map[rune]bool, len(exclude))
+	for _, c := range exclude {
+		excluded[c] = true
+	}
+
+	out := make([]rune, 0, len(ab))
+	for _, c := range ab {
+		if !excluded[c] {
+			out = append(out, c)
+		}
+	}
+	return out
+}
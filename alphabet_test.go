@@ -0,0 +1,68 @@
+package spg
+
+import "testing"
+
+func TestAllowNamedAddsAlphabet(t *testing.T) {
+	r := CharRecipe{Length: 24, Allow: 0}
+	r.AllowNamed([]string{"Base58"})
+
+	p, err := r.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	base58 := alphabetRegistry["Base58"]
+	for _, tok := range p.Tokens {
+		found := false
+		for _, c := range base58.Runes {
+			if string(c) == tok.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("token %q is not in the Base58 alphabet", tok.Value)
+		}
+	}
+}
+
+func TestExcludeNamedRemovesAlphabet(t *testing.T) {
+	r := CharRecipe{Length: 1000, Allow: 0}
+	r.AllowNamed([]string{"Hex"})
+	r.ExcludeNamed([]string{"Hex"})
+
+	if _, err := r.Generate(); err == nil {
+		t.Error("Generate() should error when AllowNamed and ExcludeNamed cancel out to an empty alphabet")
+	}
+}
+
+func TestRegisterAlphabetCustom(t *testing.T) {
+	RegisterAlphabet(Alphabet{Name: "TestOnlyXY", Runes: []rune("XY")})
+
+	r := CharRecipe{Length: 50, Allow: 0}
+	r.AllowNamed([]string{"TestOnlyXY"})
+
+	p, err := r.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	for _, tok := range p.Tokens {
+		if tok.Value != "X" && tok.Value != "Y" {
+			t.Fatalf("token %q outside registered alphabet {X,Y}", tok.Value)
+		}
+	}
+}
+
+func TestBuildCharacterListDedupesOverlappingAlphabets(t *testing.T) {
+	r := CharRecipe{Length: 1, Allow: Digits}
+	r.AllowNamed([]string{"Base62"})
+
+	chars := r.buildCharacterList()
+	seen := make(map[rune]bool)
+	for _, c := range chars {
+		if seen[c] {
+			t.Fatalf("rune %q appears more than once in buildCharacterList()", c)
+		}
+		seen[c] = true
+	}
+}
@@ -0,0 +1,172 @@
+package spg
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// GenerateN produces n passwords from the recipe far faster than calling
+// Generate in a loop: the alphabet and entropy are built once and reused
+// across every output, and the draws are fanned out across GOMAXPROCS
+// goroutines. Output order matches request order, but which goroutine
+// produced which password is unspecified. If any draw fails (for example,
+// a deterministic Reader running out of output), GenerateN returns that
+// error. When r.Reader is a deterministic Source, GenerateN runs with a
+// single worker so the (secret, site, recipe) -> output mapping stays
+// reproducible across calls instead of depending on goroutine scheduling.
+func (r CharRecipe) GenerateN(n int) ([]*Password, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("don't ask for %d passwords", n)
+	}
+	if r.Length < 1 {
+		return nil, fmt.Errorf("don't ask for passwords of length %d", r.Length)
+	}
+	if err := r.validateMinCounts(); err != nil {
+		return nil, err
+	}
+
+	chars := r.buildCharacterList()
+	if err := r.validateAlphabet(chars); err != nil {
+		return nil, err
+	}
+	entropy := r.Entropy()
+
+	out := make([]*Password, n)
+	draw := r.serializedDraw()
+
+	workers := runtime.GOMAXPROCS(0)
+	if r.Reader != nil {
+		// A shared deterministic Reader can't be drawn from concurrently
+		// without serializing, and serializing behind more than one
+		// worker still leaves which worker claims which index (and thus
+		// which slice of the keystream) up to the scheduler, breaking
+		// reproducibility. One worker keeps the mapping deterministic.
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+	perWorker := (n + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+	for w := 0; w < workers; w++ {
+		start := w * perWorker
+		end := start + perWorker
+		if start >= n {
+			break
+		}
+		if end > n {
+			end = n
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				p, err := draw(chars, entropy)
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					return
+				}
+				out[i] = p
+			}
+		}(start, end)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return out, nil
+}
+
+// GenerateStream generates passwords from the recipe and sends them to out
+// until ctx is done, using the same precompute-once, fan-out-across-workers
+// approach as GenerateN. It blocks until ctx is cancelled (or a draw
+// fails) and every worker has exited, returning the error that stopped it,
+// if any.
+func (r CharRecipe) GenerateStream(ctx context.Context, out chan<- *Password) error {
+	if r.Length < 1 {
+		return fmt.Errorf("don't ask for passwords of length %d", r.Length)
+	}
+	if err := r.validateMinCounts(); err != nil {
+		return err
+	}
+
+	chars := r.buildCharacterList()
+	if err := r.validateAlphabet(chars); err != nil {
+		return err
+	}
+	entropy := r.Entropy()
+	draw := r.serializedDraw()
+
+	workers := runtime.GOMAXPROCS(0)
+	if r.Reader != nil {
+		// A shared deterministic Reader can't be drawn from concurrently
+		// without serializing, so there's no benefit to more than one
+		// worker in that mode.
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	var errOnce sync.Once
+	var firstErr error
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+
+				p, err := draw(chars, entropy)
+				if err != nil {
+					errOnce.Do(func() { firstErr = err })
+					cancel()
+					return
+				}
+
+				select {
+				case out <- p:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return firstErr
+}
+
+// serializedDraw returns a function that generates one password from a
+// precomputed alphabet and entropy value. When r.Reader is set, the
+// returned function serializes access with a mutex, since a single
+// deterministic Reader isn't safe for concurrent use; the default CSPRNG
+// path needs no such guard.
+func (r CharRecipe) serializedDraw() func(chars []rune, entropy float32) (*Password, error) {
+	if r.Reader == nil {
+		return func(chars []rune, entropy float32) (*Password, error) {
+			return r.generateFrom(chars, entropy)
+		}
+	}
+
+	var mu sync.Mutex
+	return func(chars []rune, entropy float32) (*Password, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return r.generateFrom(chars, entropy)
+	}
+}
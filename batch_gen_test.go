@@ -0,0 +1,113 @@
+package spg
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGenerateNCount(t *testing.T) {
+	r := NewCharRecipe(12)
+	passwords, err := r.GenerateN(200)
+	if err != nil {
+		t.Fatalf("GenerateN() error: %v", err)
+	}
+	if len(passwords) != 200 {
+		t.Fatalf("len(passwords) = %d, want 200", len(passwords))
+	}
+	for i, p := range passwords {
+		if len(p.Tokens) != 12 {
+			t.Fatalf("passwords[%d] has %d tokens, want 12", i, len(p.Tokens))
+		}
+	}
+}
+
+func TestGenerateNRejectsNonPositiveN(t *testing.T) {
+	r := NewCharRecipe(12)
+	if _, err := r.GenerateN(0); err == nil {
+		t.Error("GenerateN(0) should error")
+	}
+}
+
+func TestGenerateNSurfacesDeterministicExhaustion(t *testing.T) {
+	r := NewCharRecipe(32)
+	r.Reader = NewDeterministicSource([]byte("master-secret"), []byte("example.com"))
+	if _, err := r.GenerateN(10000); err == nil {
+		t.Error("GenerateN() should surface the deterministic source's exhaustion error, not silently drop it")
+	}
+}
+
+func TestGenerateNDeterministicReproducible(t *testing.T) {
+	newRecipe := func() *CharRecipe {
+		r := NewCharRecipe(16)
+		r.Reader = NewDeterministicSource([]byte("master-secret"), []byte("example.com"))
+		return r
+	}
+
+	p1, err := newRecipe().GenerateN(50)
+	if err != nil {
+		t.Fatalf("GenerateN() error: %v", err)
+	}
+	p2, err := newRecipe().GenerateN(50)
+	if err != nil {
+		t.Fatalf("GenerateN() error: %v", err)
+	}
+
+	for i := range p1 {
+		if passwordString(p1[i]) != passwordString(p2[i]) {
+			t.Fatalf("passwords[%d] = %q, want %q (same deterministic Reader should reproduce output)", i, passwordString(p1[i]), passwordString(p2[i]))
+		}
+	}
+}
+
+func TestGenerateStreamProducesUntilCancelled(t *testing.T) {
+	r := NewCharRecipe(10)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	out := make(chan *Password)
+	done := make(chan error, 1)
+	go func() { done <- r.GenerateStream(ctx, out) }()
+
+	count := 0
+loop:
+	for {
+		select {
+		case p, ok := <-out:
+			if !ok {
+				break loop
+			}
+			if len(p.Tokens) != 10 {
+				t.Fatalf("password has %d tokens, want 10", len(p.Tokens))
+			}
+			count++
+		case <-ctx.Done():
+			break loop
+		}
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("GenerateStream() error = %v, want nil", err)
+	}
+	if count == 0 {
+		t.Error("GenerateStream() produced no passwords before the context expired")
+	}
+}
+
+func BenchmarkGenerateLoop(b *testing.B) {
+	r := NewCharRecipe(16)
+	for i := 0; i < b.N; i++ {
+		if _, err := r.Generate(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerateN(b *testing.B) {
+	r := NewCharRecipe(16)
+	for i := 0; i < b.N; i++ {
+		if _, err := r.GenerateN(1000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -2,7 +2,8 @@ package spg
 
 import (
 	"fmt"
-	"strings"
+	"io"
+	"math"
 )
 
 // Character types for Character and Separator generation
@@ -30,16 +31,6 @@ const (
 	Letters = Uppers | Lowers
 )
 
-// charTypesByFlag
-var charTypeByFlag = map[CTFlag]string{
-	Uppers:     CTUpper,
-	Lowers:     CTLower,
-	Digits:     CTDigits,
-	Symbols:    CTSymbols,
-	Ambiguous:  CTAmbiguous,
-	WhiteSpace: CTWhiteSpace,
-}
-
 /*** Character type passwords ***/
 
 // Generate a password using the character generator. The attributes contain
@@ -50,50 +41,258 @@ func (r CharRecipe) Generate() (*Password, error) {
 		return nil, fmt.Errorf("don't ask for passwords of length %d", r.Length)
 	}
 
-	p := &Password{}
+	if err := r.validateMinCounts(); err != nil {
+		return nil, err
+	}
+
 	chars := r.buildCharacterList()
+	if err := r.validateAlphabet(chars); err != nil {
+		return nil, err
+	}
+
+	return r.generateFrom(chars, r.Entropy())
+}
+
+// validateAlphabet checks that chars has enough runes to fill every
+// position MinCounts doesn't already guarantee. An Allow/Exclude (or
+// AllowNamed/ExcludeNamed) combination that cancels out to an empty
+// alphabet would otherwise panic deep in generateFrom.
+func (r CharRecipe) validateAlphabet(chars []rune) error {
+	guaranteed := 0
+	for _, n := range r.MinCounts {
+		guaranteed += n
+	}
+	if len(chars) == 0 && r.Length > guaranteed {
+		return fmt.Errorf("Allow/Exclude selections leave an empty character set to draw from")
+	}
+	return nil
+}
+
+// generateFrom builds one password using a precomputed alphabet and
+// entropy value, so callers generating many passwords from the same
+// recipe (see GenerateN, GenerateStream) can compute both once instead of
+// redoing that work on every call.
+func (r CharRecipe) generateFrom(chars []rune, entropy float32) (*Password, error) {
+	p := &Password{}
 
 	toks := make([]Token, r.Length)
-	for i := 0; i < r.Length; i++ {
-		c := chars[Int31n(uint32(len(chars)))]
-		toks[i] = Token{c, AtomTokenType}
+	filled := make([]bool, r.Length)
+	if len(r.MinCounts) > 0 {
+		if err := r.placeMinCounts(toks, filled); err != nil {
+			return nil, err
+		}
+	}
+
+	for i := range toks {
+		if filled[i] {
+			continue
+		}
+		n, err := r.intn(uint32(len(chars)))
+		if err != nil {
+			return nil, err
+		}
+		toks[i] = Token{string(chars[n]), AtomTokenType}
 	}
+
+	// Fisher-Yates shuffle so pre-placed required characters aren't
+	// clustered at the front of the password.
+	for i := len(toks) - 1; i > 0; i-- {
+		j, err := r.intn(uint32(i + 1))
+		if err != nil {
+			return nil, err
+		}
+		toks[i], toks[int(j)] = toks[int(j)], toks[i]
+	}
+
 	p.Tokens = toks
-	p.Entropy = r.Entropy()
+	p.Entropy = entropy
 	return p, nil
 }
 
-// buildCharacterList constructs the "alphabet" that is all and only those
-// characters (actually strings of length 1) that are all and only those
-// characters from which the password will be build. It also ensures that
-// there are no duplicates
-func (r CharRecipe) buildCharacterList() []string {
-
-	ab := r.IncludeExtra
-	exclude := r.ExcludeExtra
-	for f, ct := range charTypeByFlag {
-		if r.Allow&f == f {
-			ab += ct
+// intn returns a uniform random number in [0, n), drawing from r.Reader via
+// rejection sampling when one is set (see NewDeterministicSource), or from
+// the package CSPRNG otherwise. It errors if r.Reader runs out of output,
+// which HKDF-SHA512 does after 255*64 bytes.
+func (r CharRecipe) intn(n uint32) (uint32, error) {
+	if r.Reader == nil {
+		return Int31n(n), nil
+	}
+	return readIntN(r.Reader, n)
+}
+
+// validateMinCounts checks that MinCounts only constrains classes that are
+// actually allowed or required, and that the constraints can fit in Length.
+func (r CharRecipe) validateMinCounts() error {
+	if len(r.MinCounts) == 0 {
+		return nil
+	}
+
+	total := 0
+	for f, n := range r.MinCounts {
+		if n < 0 {
+			return fmt.Errorf("MinCounts for %v may not be negative", f)
 		}
-		// Treat Require as Allow for now
-		if r.Require&f == f {
-			ab += ct
+		if r.Allow&f != f && r.Require&f != f {
+			return fmt.Errorf("MinCounts specifies class %v which is neither allowed nor required", f)
+		}
+		if n > 0 && len(r.classAlphabet(f)) == 0 {
+			return fmt.Errorf("MinCounts requires %d characters from class %v, but Exclude/ExcludeExtra/ExcludeNames leave it with none", n, f)
+		}
+		total += n
+	}
+	if total > r.Length {
+		return fmt.Errorf("MinCounts totals %d, which is more than the requested length %d", total, r.Length)
+	}
+	return nil
+}
+
+// placeMinCounts pre-places, at random positions within toks, at least
+// MinCounts[f] characters drawn from each class f's alphabet, marking each
+// filled position in filled. Positions left false are filled by Generate
+// from the full alphabet.
+func (r CharRecipe) placeMinCounts(toks []Token, filled []bool) error {
+	available := make([]int, len(toks))
+	for i := range available {
+		available[i] = i
+	}
+
+	for f, n := range r.MinCounts {
+		ab := r.classAlphabet(f)
+		if len(ab) == 0 || n == 0 {
+			continue
 		}
-		if r.Exclude&f == f {
-			exclude += ct
+		for ; n > 0; n-- {
+			k, err := r.intn(uint32(len(available)))
+			if err != nil {
+				return err
+			}
+			pos := available[k]
+			available[k] = available[len(available)-1]
+			available = available[:len(available)-1]
+
+			c, err := r.intn(uint32(len(ab)))
+			if err != nil {
+				return err
+			}
+			toks[pos] = Token{string(ab[c]), AtomTokenType}
+			filled[pos] = true
+		}
+	}
+	return nil
+}
+
+// classAlphabet returns the (Exclude/ExcludeExtra/ExcludeNames-filtered)
+// alphabet for a single character class flag.
+func (r CharRecipe) classAlphabet(f CTFlag) []rune {
+	var ab []rune
+	for _, name := range alphabetNames() {
+		if a := alphabetRegistry[name]; a.Flag != 0 && a.Flag == f {
+			ab = append(ab, a.Runes...)
+		}
+	}
+	return dedupeRunes(subtractRunes(ab, r.excludedRunes()))
+}
+
+// buildCharacterList constructs the "alphabet" that is all and only those
+// runes from which the password will be built, as selected by
+// Allow/Require/AllowNames and with Exclude/ExcludeExtra/ExcludeNames
+// removed.
+func (r CharRecipe) buildCharacterList() []rune {
+
+	ab := []rune(r.IncludeExtra)
+	for _, name := range alphabetNames() {
+		a := alphabetRegistry[name]
+		// Treat Require as Allow for now
+		if (a.Flag != 0 && (r.Allow&a.Flag == a.Flag || r.Require&a.Flag == a.Flag)) || nameIn(r.AllowNames, name) {
+			ab = append(ab, a.Runes...)
 		}
 	}
 
-	alphabet := subtractString(ab, exclude)
-	return strings.Split(alphabet, "")
+	return dedupeRunes(subtractRunes(ab, r.excludedRunes()))
+}
+
+// excludedRunes gathers every rune that Exclude, ExcludeExtra and
+// ExcludeNames remove from the alphabet.
+func (r CharRecipe) excludedRunes() []rune {
+	exclude := []rune(r.ExcludeExtra)
+	for _, name := range alphabetNames() {
+		a := alphabetRegistry[name]
+		if (a.Flag != 0 && r.Exclude&a.Flag == a.Flag) || nameIn(r.ExcludeNames, name) {
+			exclude = append(exclude, a.Runes...)
+		}
+	}
+	return exclude
 }
 
 // Entropy returns the entropy of a character password given the generator attributes
 func (r CharRecipe) Entropy() float32 {
+	if len(r.MinCounts) > 0 {
+		return float32(r.entropyWithMinCounts())
+	}
 	size := len(r.buildCharacterList())
 	return float32(entropySimple(r.Length, size))
 }
 
+// entropyWithMinCounts computes the entropy of a password whose characters
+// are constrained by MinCounts. Rather than treating every position as an
+// independent draw from the full alphabet (which overstates the entropy
+// once some positions are guaranteed to come from smaller classes), this
+// counts the log2 of the number of distinct arrangements: the multinomial
+// coefficient for how the guaranteed/free positions can be assigned, times
+// the number of character choices each position allows.
+func (r CharRecipe) entropyWithMinCounts() float64 {
+	freeSize := len(r.buildCharacterList())
+
+	guaranteed := 0
+	bits := 0.0
+	counts := make([]int, 0, len(r.MinCounts))
+	for f, n := range r.MinCounts {
+		if n == 0 {
+			continue
+		}
+		classSize := len(r.classAlphabet(f))
+		counts = append(counts, n)
+		guaranteed += n
+		bits += float64(n) * log2(float64(classSize))
+	}
+
+	free := r.Length - guaranteed
+	bits += float64(free) * log2(float64(freeSize))
+
+	counts = append(counts, free)
+	bits += multinomialLog2(r.Length, counts)
+
+	return bits
+}
+
+// log2 is a small convenience wrapper since math.Log2 operates on float64.
+func log2(x float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	return math.Log2(x)
+}
+
+// logFactorial2 returns log2(n!) computed via the log-gamma function so it
+// stays accurate for large n.
+func logFactorial2(n int) float64 {
+	if n < 2 {
+		return 0
+	}
+	lg, _ := math.Lgamma(float64(n) + 1)
+	return lg / math.Ln2
+}
+
+// multinomialLog2 returns log2 of the multinomial coefficient n! / (k1! *
+// k2! * ... * km!) for the given group sizes, which must sum to n.
+func multinomialLog2(n int, groups []int) float64 {
+	bits := logFactorial2(n)
+	for _, k := range groups {
+		bits -= logFactorial2(k)
+	}
+	return bits
+}
+
 // CharInclusion holds the inclusion/exclusion value for some character class
 type CharInclusion int
 
@@ -108,12 +307,16 @@ const (
 
 // CharRecipe are generator attributes relevent for character list generation
 type CharRecipe struct {
-	Length       int    // Length of generated password in characters
-	Allow        CTFlag // Flags for which character types to allow
-	Require      CTFlag // Flags for which character types to require
-	Exclude      CTFlag // Flags for which character types to exclude
-	ExcludeExtra string // Specific characters caller may want excluded
-	IncludeExtra string // Specific characters caller may want excluded (this is where to put emojis. Please don't)
+	Length       int            // Length of generated password in characters
+	Allow        CTFlag         // Flags for which character types to allow
+	Require      CTFlag         // Flags for which character types to require
+	Exclude      CTFlag         // Flags for which character types to exclude
+	ExcludeExtra string         // Specific characters caller may want excluded
+	IncludeExtra string         // Specific characters caller may want excluded (this is where to put emojis. Please don't)
+	MinCounts    map[CTFlag]int // Minimum number of characters required from each class, e.g. {Digits: 2}
+	Reader       io.Reader      // Source of randomness; nil uses the package CSPRNG (see NewDeterministicSource)
+	AllowNames   []string       // Names of registered Alphabets to allow, e.g. {"Base58"} (see RegisterAlphabet)
+	ExcludeNames []string       // Names of registered Alphabets to exclude
 }
 
 // We need a way to map certain field names to the alphabets they correspond to
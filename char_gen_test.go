@@ -0,0 +1,113 @@
+package spg
+
+import (
+	"strings"
+	"testing"
+)
+
+func passwordString(p *Password) string {
+	var b strings.Builder
+	for _, t := range p.Tokens {
+		b.WriteString(t.Value)
+	}
+	return b.String()
+}
+
+func TestNewCharRecipeDefaults(t *testing.T) {
+	r := NewCharRecipe(12)
+	if r.Length != 12 {
+		t.Errorf("Length = %d, want 12", r.Length)
+	}
+	if r.Allow != Letters|Digits|Symbols {
+		t.Errorf("Allow = %v, want Letters|Digits|Symbols", r.Allow)
+	}
+	if r.Exclude != Ambiguous {
+		t.Errorf("Exclude = %v, want Ambiguous", r.Exclude)
+	}
+}
+
+func TestGenerateLength(t *testing.T) {
+	r := NewCharRecipe(20)
+	p, err := r.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+	if got := len(p.Tokens); got != 20 {
+		t.Errorf("len(Tokens) = %d, want 20", got)
+	}
+}
+
+func TestGenerateRejectsNonPositiveLength(t *testing.T) {
+	r := NewCharRecipe(0)
+	if _, err := r.Generate(); err == nil {
+		t.Error("Generate() with Length 0 should error")
+	}
+}
+
+func TestMinCountsGuaranteesClassCounts(t *testing.T) {
+	r := NewCharRecipe(10)
+	r.MinCounts = map[CTFlag]int{Digits: 3, Symbols: 2}
+
+	for i := 0; i < 50; i++ {
+		p, err := r.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		s := passwordString(p)
+		digits := 0
+		symbols := 0
+		for _, c := range s {
+			if strings.ContainsRune(CTDigits, c) {
+				digits++
+			}
+			if strings.ContainsRune(CTSymbols, c) {
+				symbols++
+			}
+		}
+		if digits < 3 {
+			t.Fatalf("password %q has %d digits, want >= 3", s, digits)
+		}
+		if symbols < 2 {
+			t.Fatalf("password %q has %d symbols, want >= 2", s, symbols)
+		}
+	}
+}
+
+func TestMinCountsRejectsExcessiveTotal(t *testing.T) {
+	r := NewCharRecipe(4)
+	r.MinCounts = map[CTFlag]int{Digits: 3, Symbols: 2}
+	if _, err := r.Generate(); err == nil {
+		t.Error("Generate() should reject MinCounts totalling more than Length")
+	}
+}
+
+func TestMinCountsRejectsUnallowedClass(t *testing.T) {
+	r := NewCharRecipe(10)
+	r.Allow = Digits
+	r.MinCounts = map[CTFlag]int{Symbols: 1}
+	if _, err := r.Generate(); err == nil {
+		t.Error("Generate() should reject MinCounts for a class that isn't allowed")
+	}
+}
+
+func TestMinCountsRejectsClassEmptiedByExclude(t *testing.T) {
+	r := NewCharRecipe(10)
+	r.Allow = Digits | Uppers
+	r.ExcludeExtra = CTDigits
+	r.MinCounts = map[CTFlag]int{Digits: 1}
+	if _, err := r.Generate(); err == nil {
+		t.Error("Generate() should reject MinCounts for a class ExcludeExtra leaves empty")
+	}
+}
+
+func TestEntropyWithMinCountsIsLowerThanUnconstrained(t *testing.T) {
+	r := NewCharRecipe(10)
+	unconstrained := r.Entropy()
+
+	r.MinCounts = map[CTFlag]int{Digits: 5}
+	constrained := r.Entropy()
+
+	if constrained >= unconstrained {
+		t.Errorf("entropy with MinCounts = %v, want less than unconstrained %v", constrained, unconstrained)
+	}
+}
@@ -0,0 +1,48 @@
+package spg
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// NewDeterministicSource returns an io.Reader that derives a deterministic
+// byte stream from masterSecret and siteName via HKDF-SHA512, with
+// siteName as the HKDF info parameter. Setting CharRecipe.Reader to the
+// result makes Generate produce the same password every time for a given
+// (masterSecret, siteName, recipe) triple, enabling "stateless password
+// manager" style derivation instead of drawing from the CSPRNG.
+func NewDeterministicSource(masterSecret, siteName []byte) io.Reader {
+	return hkdf.New(sha512.New, masterSecret, nil, siteName)
+}
+
+// readIntN draws a uniform random number in [0, n) from r using rejection
+// sampling, so that a deterministic Reader (see NewDeterministicSource)
+// preserves the same uniform distribution over len(chars) that Int31n
+// provides for the default CSPRNG path. It returns an error if r can't
+// produce another 4 bytes, which HKDF-SHA512 does once 255*64 bytes have
+// been read from it.
+func readIntN(r io.Reader, n uint32) (uint32, error) {
+	if n == 0 {
+		return 0, nil
+	}
+
+	// Largest multiple of n not above 2^31-1, the top of the 31-bit draw
+	// below; draws landing at or above it are rejected and redrawn so every
+	// value in [0, n) is equally likely.
+	limit := (uint32(1)<<31 - 1) - (uint32(1)<<31-1)%n
+
+	var buf [4]byte
+	for {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return 0, fmt.Errorf("spg: deterministic source exhausted: %w", err)
+		}
+		v := binary.BigEndian.Uint32(buf[:]) & 0x7fffffff
+		if v < limit {
+			return v % n, nil
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package spg
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// koremutakeSyllables is the fixed Koremutake-style table of CV, CCV and CCCV syllables
+// used to build pronounceable passwords. It is not meant to be exhaustive
+// Koremutake, just a fixed, well-distributed syllable set.
+var koremutakeSyllables = []string{
+	"ba", "be", "bi", "bo", "bu", "by",
+	"da", "de", "di", "do", "du", "dy",
+	"fa", "fe", "fi", "fo", "fu", "fy",
+	"ga", "ge", "gi", "go", "gu", "gy",
+	"ha", "he", "hi", "ho", "hu", "hy",
+	"ja", "je", "ji", "jo", "ju", "jy",
+	"ka", "ke", "ki", "ko", "ku", "ky",
+	"la", "le", "li", "lo", "lu", "ly",
+	"ma", "me", "mi", "mo", "mu", "my",
+	"na", "ne", "ni", "no", "nu", "ny",
+	"pa", "pe", "pi", "po", "pu", "py",
+	"ra", "re", "ri", "ro", "ru", "ry",
+	"sa", "se", "si", "so", "su", "sy",
+	"ta", "te", "ti", "to", "tu", "ty",
+	"va", "ve", "vi", "vo", "vu", "vy",
+	"bra", "bre", "bri", "bro", "bru",
+	"dra", "dre", "dri", "dro", "dru",
+	"gra", "gre", "gri", "gro", "gru",
+	"kra", "kre", "kri", "kro", "kru",
+	"pra", "pre", "pri", "pro", "pru",
+	"tra", "tre", "tri", "tro", "tru",
+	"stra", "stre", "stri", "stro", "stru",
+}
+
+// SyllableTokenType marks a Token as a pronounceable syllable drawn from a
+// SyllableRecipe, as opposed to a single-character AtomTokenType.
+const SyllableTokenType = "syllable"
+
+// CapitalizeMode controls how (if at all) syllables are capitalized when
+// generating a SyllableRecipe password.
+type CapitalizeMode int
+
+// CapNone leaves syllables as-is, CapFirst capitalizes only the first
+// syllable, and CapRandom capitalizes each syllable independently at random.
+const (
+	CapNone CapitalizeMode = iota
+	CapFirst
+	CapRandom
+)
+
+// SyllableRecipe generates pronounceable passwords by concatenating
+// syllables from a fixed table, optionally interleaved with digits and
+// symbols. It complements CharRecipe for users who want something
+// memorable without pulling in a wordlist.
+type SyllableRecipe struct {
+	Length       int            // Number of syllables
+	Capitalize   CapitalizeMode // How to capitalize syllables
+	DigitsAfter  int            // Inject a digit every DigitsAfter syllables (0 disables)
+	SymbolsAfter int            // Inject a symbol every SymbolsAfter syllables (0 disables)
+}
+
+// Generate builds a password from the syllable table, injecting digits and
+// symbols between syllables as configured.
+func (r SyllableRecipe) Generate() (*Password, error) {
+
+	if r.Length < 1 {
+		return nil, fmt.Errorf("don't ask for passwords of length %d", r.Length)
+	}
+
+	p := &Password{}
+	var toks []Token
+
+	for i := 0; i < r.Length; i++ {
+		syl := koremutakeSyllables[Int31n(uint32(len(koremutakeSyllables)))]
+		syl = r.capitalize(syl, i)
+		toks = append(toks, Token{syl, SyllableTokenType})
+
+		if r.DigitsAfter > 0 && (i+1)%r.DigitsAfter == 0 && i != r.Length-1 {
+			d := string(CTDigits[Int31n(uint32(len(CTDigits)))])
+			toks = append(toks, Token{d, AtomTokenType})
+		}
+		if r.SymbolsAfter > 0 && (i+1)%r.SymbolsAfter == 0 && i != r.Length-1 {
+			s := string(CTSymbols[Int31n(uint32(len(CTSymbols)))])
+			toks = append(toks, Token{s, AtomTokenType})
+		}
+	}
+
+	p.Tokens = toks
+	p.Entropy = r.Entropy()
+	return p, nil
+}
+
+// capitalize applies r.Capitalize to syl, where i is the syllable's index
+// (0-based) within the password.
+func (r SyllableRecipe) capitalize(syl string, i int) string {
+	switch r.Capitalize {
+	case CapFirst:
+		if i == 0 {
+			return capitalizeSyllable(syl)
+		}
+	case CapRandom:
+		if Int31n(2) == 0 {
+			return capitalizeSyllable(syl)
+		}
+	}
+	return syl
+}
+
+// capitalizeSyllable upper-cases the first letter of a syllable.
+func capitalizeSyllable(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// injections returns the number of digit and symbol tokens this recipe will
+// inject, used by Entropy.
+func (r SyllableRecipe) injections() (digits, symbols int) {
+	if r.DigitsAfter > 0 {
+		digits = (r.Length - 1) / r.DigitsAfter
+	}
+	if r.SymbolsAfter > 0 {
+		symbols = (r.Length - 1) / r.SymbolsAfter
+	}
+	return
+}
+
+// Entropy returns the entropy of a syllable password: the syllables'
+// contribution plus the entropy added by any injected digits/symbols.
+func (r SyllableRecipe) Entropy() float32 {
+	syllableBits := float64(r.Length) * math.Log2(float64(len(koremutakeSyllables)))
+
+	digits, symbols := r.injections()
+	injectionBits := float64(digits)*math.Log2(float64(len(CTDigits))) +
+		float64(symbols)*math.Log2(float64(len(CTSymbols)))
+
+	return float32(syllableBits + injectionBits)
+}
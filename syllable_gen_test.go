@@ -0,0 +1,87 @@
+package spg
+
+import "testing"
+
+func TestSyllableRecipeGenerateLength(t *testing.T) {
+	r := SyllableRecipe{Length: 4}
+	p, err := r.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	syllables := 0
+	for _, tok := range p.Tokens {
+		if tok.Type == SyllableTokenType {
+			syllables++
+		}
+	}
+	if syllables != 4 {
+		t.Errorf("got %d syllable tokens, want 4", syllables)
+	}
+}
+
+func TestSyllableRecipeRejectsNonPositiveLength(t *testing.T) {
+	r := SyllableRecipe{Length: 0}
+	if _, err := r.Generate(); err == nil {
+		t.Error("Generate() with Length 0 should error")
+	}
+}
+
+func TestSyllableRecipeInjectsDigitsAndSymbols(t *testing.T) {
+	r := SyllableRecipe{Length: 4, DigitsAfter: 2, SymbolsAfter: 3}
+	p, err := r.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error: %v", err)
+	}
+
+	var digits, symbols, syllables int
+	for _, tok := range p.Tokens {
+		switch {
+		case tok.Type == SyllableTokenType:
+			syllables++
+		case tok.Value != "" && tok.Value[0] >= '0' && tok.Value[0] <= '9':
+			digits++
+		default:
+			symbols++
+		}
+	}
+	if syllables != 4 {
+		t.Errorf("got %d syllables, want 4", syllables)
+	}
+	if digits == 0 {
+		t.Error("expected at least one injected digit")
+	}
+	if symbols == 0 {
+		t.Error("expected at least one injected symbol")
+	}
+}
+
+func TestSyllableRecipeCapFirst(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		r := SyllableRecipe{Length: 3, Capitalize: CapFirst}
+		p, err := r.Generate()
+		if err != nil {
+			t.Fatalf("Generate() error: %v", err)
+		}
+		first := p.Tokens[0].Value
+		if first[0] < 'A' || first[0] > 'Z' {
+			t.Fatalf("first syllable %q not capitalized", first)
+		}
+		for _, tok := range p.Tokens[1:] {
+			if tok.Type != SyllableTokenType {
+				continue
+			}
+			if tok.Value[0] >= 'A' && tok.Value[0] <= 'Z' {
+				t.Fatalf("non-first syllable %q unexpectedly capitalized", tok.Value)
+			}
+		}
+	}
+}
+
+func TestSyllableRecipeEntropyIncreasesWithLength(t *testing.T) {
+	short := SyllableRecipe{Length: 2}
+	long := SyllableRecipe{Length: 6}
+	if long.Entropy() <= short.Entropy() {
+		t.Errorf("Entropy(6 syllables) = %v, want greater than Entropy(2 syllables) = %v", long.Entropy(), short.Entropy())
+	}
+}
@@ -0,0 +1,241 @@
+package spg
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keyboardRows are the rows whose runs Strength penalizes like sequential
+// runs, since they're no harder to type than alphabetic runs. They are
+// scored separately and reported as CriterionKeyboardRow.
+var keyboardRows = []string{
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+	"1234567890",
+}
+
+// ValidationCriterion names the specific check that caused Validate to
+// reject a password.
+type ValidationCriterion string
+
+// The criteria Validate can name in a ValidationError. CriterionEntropy
+// means the password was simply too short/too narrow an alphabet once any
+// pattern penalties are accounted for; the others mean a pattern penalty
+// was the dominant reason the password fell short.
+const (
+	CriterionEntropy       ValidationCriterion = "entropy"
+	CriterionRepeatedRun   ValidationCriterion = "repeated-run"
+	CriterionSequentialRun ValidationCriterion = "sequential-run"
+	CriterionKeyboardRow   ValidationCriterion = "keyboard-row"
+)
+
+// ValidationError reports that a password failed Validate, naming which
+// criterion was responsible so callers can give a specific reason instead
+// of a bare "too weak".
+type ValidationError struct {
+	Criterion  ValidationCriterion
+	Entropy    float64
+	MinEntropy float64
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("password failed %s check: entropy %.1f bits is below required minimum of %.1f bits", e.Criterion, e.Entropy, e.MinEntropy)
+}
+
+// Validate returns a *ValidationError if password's estimated Strength is
+// below minEntropy, naming which criterion failed. It uses the same
+// alphabet model as CharRecipe, so callers that generate passwords with
+// this package can gate user-supplied passwords through the same
+// yardstick.
+func Validate(password string, minEntropy float64) error {
+	b := computeStrength(password)
+	if b.total >= minEntropy {
+		return nil
+	}
+	return &ValidationError{
+		Criterion:  b.dominantCriterion(),
+		Entropy:    b.total,
+		MinEntropy: minEntropy,
+	}
+}
+
+// Strength estimates the Shannon entropy, in bits, of an arbitrary
+// password string. It detects which character classes (CTUpper, CTLower,
+// CTDigits, CTSymbols, CTAmbiguous, CTWhiteSpace) are present to compute an
+// effective alphabet size N, starts from len(password) * log2(N), and then
+// subtracts penalties for repeated runs ("aaaa"), sequential runs ("abcd",
+// "1234") and keyboard-row runs ("qwerty").
+func Strength(password string) float64 {
+	return computeStrength(password).total
+}
+
+// strengthBits breaks Strength's result down into its base entropy figure
+// and the individual penalties subtracted from it, so Validate can name
+// which one is responsible for a rejection.
+type strengthBits struct {
+	base     float64
+	repeat   float64
+	sequence float64
+	keyboard float64
+	total    float64
+}
+
+// dominantCriterion returns whichever penalty subtracted the most bits, or
+// CriterionEntropy if none of them did (the password was simply too short
+// or drawn from too small an alphabet).
+func (b strengthBits) dominantCriterion() ValidationCriterion {
+	criterion := CriterionEntropy
+	worst := 0.0
+	if b.repeat > worst {
+		worst = b.repeat
+		criterion = CriterionRepeatedRun
+	}
+	if b.sequence > worst {
+		worst = b.sequence
+		criterion = CriterionSequentialRun
+	}
+	if b.keyboard > worst {
+		criterion = CriterionKeyboardRow
+	}
+	return criterion
+}
+
+// computeStrength computes Strength's result along with the components
+// that fed into it.
+func computeStrength(password string) strengthBits {
+	if password == "" {
+		return strengthBits{}
+	}
+
+	n := alphabetSize(password)
+	base := float64(len([]rune(password))) * log2(float64(n))
+	repeat := repeatPenalty(password)
+	sequence := sequencePenalty(password)
+	keyboard := keyboardRowPenalty(password)
+
+	total := base - repeat - sequence - keyboard
+	if total < 0 {
+		total = 0
+	}
+
+	return strengthBits{base: base, repeat: repeat, sequence: sequence, keyboard: keyboard, total: total}
+}
+
+// alphabetSize returns the combined size of every character class present
+// in password.
+func alphabetSize(password string) int {
+	classes := []string{CTUpper, CTLower, CTDigits, CTSymbols, CTAmbiguous, CTWhiteSpace}
+	size := 0
+	for _, ct := range classes {
+		if strings.ContainsAny(password, ct) {
+			size += len([]rune(ct))
+		}
+	}
+	if size == 0 {
+		size = len([]rune(password))
+	}
+	return size
+}
+
+// repeatPenalty returns bits to subtract for runs of the same character
+// repeated 3 or more times, such as "aaaa".
+func repeatPenalty(password string) float64 {
+	runes := []rune(password)
+	bits := 0.0
+	run := 1
+	for i := 1; i <= len(runes); i++ {
+		if i < len(runes) && runes[i] == runes[i-1] {
+			run++
+			continue
+		}
+		if run >= 3 {
+			bits += float64(run-1) * 2
+		}
+		run = 1
+	}
+	return bits
+}
+
+// sequencePenalty returns bits to subtract for ascending or descending runs
+// of 3 or more consecutive code points, such as "abcd" or "4321".
+func sequencePenalty(password string) float64 {
+	runes := []rune(password)
+	bits := 0.0
+	run := 1
+	var dir rune
+	for i := 1; i <= len(runes); i++ {
+		var step rune
+		if i < len(runes) {
+			step = runes[i] - runes[i-1]
+		}
+		if (step == 1 || step == -1) && (run == 1 || step == dir) {
+			run++
+			dir = step
+			continue
+		}
+		if run >= 3 {
+			bits += float64(run-1) * 2
+		}
+		run = 1
+		if step == 1 || step == -1 {
+			// A step in the opposite direction starts a new run.
+			run = 2
+			dir = step
+		}
+	}
+	return bits
+}
+
+// keyboardRowPenalty returns bits to subtract for substrings of 3 or more
+// characters that appear consecutively (forwards or backwards) in a
+// keyboard row, such as "qwerty" or "asdf".
+func keyboardRowPenalty(password string) float64 {
+	lower := strings.ToLower(password)
+	bits := 0.0
+	for _, row := range keyboardRows {
+		bits += rowRunBits(lower, row)
+		bits += rowRunBits(lower, reverseString(row))
+	}
+	return bits
+}
+
+// rowRunBits scans haystack for runs of 3 or more consecutive characters
+// that appear in the same order within row, and returns the bits to
+// subtract for them.
+func rowRunBits(haystack, row string) float64 {
+	bits := 0.0
+	run := 0
+	pos := -1
+	for _, c := range haystack {
+		idx := strings.IndexRune(row, c)
+		if idx >= 0 && idx == pos+1 {
+			run++
+			pos = idx
+			continue
+		}
+		if run >= 3 {
+			bits += float64(run-1) * 2
+		}
+		if idx >= 0 {
+			run = 1
+			pos = idx
+		} else {
+			run = 0
+			pos = -1
+		}
+	}
+	if run >= 3 {
+		bits += float64(run-1) * 2
+	}
+	return bits
+}
+
+// reverseString returns s with its runes in reverse order.
+func reverseString(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
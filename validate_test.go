@@ -0,0 +1,73 @@
+package spg
+
+import "testing"
+
+func TestStrengthIncreasesWithLength(t *testing.T) {
+	short := Strength("abcXYZ12")
+	long := Strength("abcXYZ12abcXYZ12")
+	if long <= short {
+		t.Errorf("Strength(long) = %v, want greater than Strength(short) = %v", long, short)
+	}
+}
+
+func TestStrengthEmptyPassword(t *testing.T) {
+	if got := Strength(""); got != 0 {
+		t.Errorf("Strength(\"\") = %v, want 0", got)
+	}
+}
+
+func TestStrengthPenalizesRepeatedRuns(t *testing.T) {
+	repeated := Strength("aaaaaaaaaa")
+	varied := Strength("a1B2c3D4e5")
+	if repeated >= varied {
+		t.Errorf("Strength(repeated) = %v, want less than Strength(varied) = %v", repeated, varied)
+	}
+}
+
+func TestValidateAcceptsStrongPassword(t *testing.T) {
+	if err := Validate("xQ7!mZ2p9#Lw", 20); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+}
+
+func TestValidateNamesEntropyCriterion(t *testing.T) {
+	err := Validate("ab", 40)
+	if err == nil {
+		t.Fatal("Validate() should reject a short password")
+	}
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Validate() error type = %T, want *ValidationError", err)
+	}
+	if verr.Criterion != CriterionEntropy {
+		t.Errorf("Criterion = %v, want %v", verr.Criterion, CriterionEntropy)
+	}
+}
+
+func TestValidateNamesRepeatedRunCriterion(t *testing.T) {
+	err := Validate("aaaaaaaaaa", 200)
+	if err == nil {
+		t.Fatal("Validate() should reject a long repeated run")
+	}
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Validate() error type = %T, want *ValidationError", err)
+	}
+	if verr.Criterion != CriterionRepeatedRun {
+		t.Errorf("Criterion = %v, want %v", verr.Criterion, CriterionRepeatedRun)
+	}
+}
+
+func TestValidateNamesKeyboardRowCriterion(t *testing.T) {
+	err := Validate("qwertyuiopqwertyuiop", 55)
+	if err == nil {
+		t.Fatal("Validate() should reject a keyboard-row password")
+	}
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Validate() error type = %T, want *ValidationError", err)
+	}
+	if verr.Criterion != CriterionKeyboardRow {
+		t.Errorf("Criterion = %v, want %v", verr.Criterion, CriterionKeyboardRow)
+	}
+}